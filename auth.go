@@ -0,0 +1,149 @@
+package emhcasa
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// AuthMethod authenticates outgoing requests to a CASA gateway, either
+// by wrapping the HTTP round tripper (Digest, Basic, Bearer) or by
+// supplying TLS client credentials (mTLS).
+type AuthMethod interface {
+	// Transport wraps base with whatever RoundTripper logic this auth
+	// method needs to authenticate requests. Implementations that
+	// authenticate at the TLS layer instead may return base unchanged.
+	Transport(base http.RoundTripper) http.RoundTripper
+}
+
+// tlsAuthMethod is implemented by AuthMethod implementations that need
+// to configure the underlying TLS client transport. Currently only
+// mTLS does.
+type tlsAuthMethod interface {
+	tlsConfig() *tls.Config
+}
+
+// digestAuth implements HTTP Digest authentication (RFC 7616), the
+// scheme CASA gateways expose out of the box.
+type digestAuth struct {
+	user, password string
+}
+
+// NewDigestAuth creates an AuthMethod that authenticates using HTTP
+// Digest.
+func NewDigestAuth(user, password string) AuthMethod {
+	return &digestAuth{user: user, password: password}
+}
+
+func (a *digestAuth) Transport(base http.RoundTripper) http.RoundTripper {
+	return NewDigestTransport(a.user, a.password, base)
+}
+
+// basicAuth implements HTTP Basic authentication.
+type basicAuth struct {
+	user, password string
+}
+
+// NewBasicAuth creates an AuthMethod that authenticates using HTTP
+// Basic, for gateways placed behind a reverse proxy that terminates
+// Digest itself.
+func NewBasicAuth(user, password string) AuthMethod {
+	return &basicAuth{user: user, password: password}
+}
+
+func (a *basicAuth) Transport(base http.RoundTripper) http.RoundTripper {
+	return &basicAuthTransport{user: a.user, password: a.password, base: base}
+}
+
+type basicAuthTransport struct {
+	user, password string
+	base           http.RoundTripper
+}
+
+func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = cloneRequest(req)
+	req.SetBasicAuth(t.user, t.password)
+	return t.base.RoundTrip(req)
+}
+
+// bearerAuth implements HTTP Bearer token authentication.
+type bearerAuth struct {
+	token string
+}
+
+// NewBearerAuth creates an AuthMethod that sends token as a Bearer
+// Authorization header, for gateways fronted by a token-based proxy.
+func NewBearerAuth(token string) AuthMethod {
+	return &bearerAuth{token: token}
+}
+
+func (a *bearerAuth) Transport(base http.RoundTripper) http.RoundTripper {
+	return &bearerAuthTransport{token: a.token, base: base}
+}
+
+type bearerAuthTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *bearerAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = cloneRequest(req)
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// mtlsAuth implements client-certificate authentication. Unlike the
+// other AuthMethods it doesn't wrap the RoundTripper: NewClient applies
+// its TLS config to the transport directly instead of layering an
+// Authorization header on top. A config passed with WithTLSConfig
+// replaces it, client certificate included.
+type mtlsAuth struct {
+	cfg *tls.Config
+}
+
+// NewMTLSAuth creates an AuthMethod that authenticates via a client
+// certificate, for gateways or reverse proxies configured to require
+// mutual TLS instead of an application-layer credential. caFile may be
+// empty to verify the server against the system root pool.
+func NewMTLSAuth(certFile, keyFile, caFile string) (AuthMethod, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate: %s", caFile)
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	return &mtlsAuth{cfg: cfg}, nil
+}
+
+func (a *mtlsAuth) Transport(base http.RoundTripper) http.RoundTripper {
+	return base
+}
+
+func (a *mtlsAuth) tlsConfig() *tls.Config {
+	return a.cfg
+}
+
+// cloneRequest returns a shallow copy of req suitable for adding
+// authentication headers without mutating the caller's request.
+func cloneRequest(req *http.Request) *http.Request {
+	return req.Clone(req.Context())
+}
@@ -10,64 +10,87 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Client is a CASA 1.1 smart meter gateway client.
-// It handles HTTP digest authentication, custom host headers, and meter data retrieval.
+// It handles authentication, custom host headers, and meter data retrieval.
 type Client struct {
 	httpClient *http.Client
 	uri        string
 	meterID    string
 }
 
-// NewClient creates a new CASA client with HTTP digest authentication.
+// NewClient creates a new CASA client.
 //
-// Parameters:
-//   - uri: Gateway URI (http or https, e.g., "https://192.168.33.2")
-//   - user: Username for digest authentication
-//   - password: Password for digest authentication
-//   - meterID: Meter ID to use (empty string to auto-discover from available contracts)
-//   - hostHeader: Custom Host header for routing (typically the gateway IP)
+// uri is the gateway URI (http or https, e.g., "https://192.168.33.2").
+// meterID is the meter ID to use, or "" to auto-discover from available
+// contracts. Authentication and transport are configured via opts; at
+// minimum, pass WithAuth unless WithHTTPClient supplies an
+// already-authenticated client.
 //
 // The client automatically discovers the meter ID if not provided.
-// Returns an error if credentials are missing or meter ID discovery fails.
-func NewClient(uri, user, password, meterID, hostHeader string) (*Client, error) {
+// Returns an error if uri or an auth method is missing, or meter ID
+// discovery fails.
+func NewClient(uri, meterID string, opts ...Option) (*Client, error) {
 	if uri == "" {
 		return nil, fmt.Errorf("uri is required")
 	}
 
-	if user == "" || password == "" {
-		return nil, fmt.Errorf("credentials are required")
+	cfg := &clientOptions{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
 	uri = defaultScheme(uri, "https")
-	host := hostHeader
 
-	// If no host provided, try to derive from URI
-	if host == "" {
-		derived, err := parseURIHost(uri)
-		if err != nil {
-			return nil, fmt.Errorf("host required and could not be derived: %w", err)
+	httpClient := cfg.httpClient
+	if httpClient == nil {
+		if cfg.auth == nil {
+			return nil, fmt.Errorf("authentication method is required")
 		}
-		host = derived
-	}
 
-	// Create HTTP client with custom transport for self-signed certs
-	customTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
-		ForceAttemptHTTP2: false,
-	}
+		host := cfg.hostHeader
 
-	hostTransport := &hostHeaderTransport{
-		base: customTransport,
-		host: host,
-	}
+		// If no host provided, try to derive from URI
+		if host == "" {
+			derived, err := parseURIHost(uri)
+			if err != nil {
+				return nil, fmt.Errorf("host required and could not be derived: %w", err)
+			}
+			host = derived
+		}
+
+		tlsConfig := cfg.tlsConfig
+		if tlsConfig == nil {
+			if tlsAuth, ok := cfg.auth.(tlsAuthMethod); ok {
+				// mTLS supplies its own client certificate and CA pool,
+				// so unlike the other auth methods it verifies the
+				// server normally by default.
+				tlsConfig = tlsAuth.tlsConfig()
+			} else {
+				// CASA gateways serve self-signed certificates out of
+				// the box, so Digest/Basic/Bearer keep the historical
+				// default of skipping verification. Pass WithTLSConfig
+				// to verify against a specific CA instead.
+				tlsConfig = &tls.Config{InsecureSkipVerify: true}
+			}
+		}
 
-	// Add digest authentication
-	httpClient := &http.Client{
-		Transport: NewDigestTransport(user, password, hostTransport),
+		customTransport := &http.Transport{
+			TLSClientConfig:   tlsConfig,
+			ForceAttemptHTTP2: false,
+		}
+
+		hostTransport := &hostHeaderTransport{
+			base: customTransport,
+			host: host,
+		}
+
+		httpClient = &http.Client{
+			Transport: cfg.auth.Transport(hostTransport),
+			Timeout:   cfg.timeout,
+		}
 	}
 
 	c := &Client{
@@ -86,6 +109,12 @@ func NewClient(uri, user, password, meterID, hostHeader string) (*Client, error)
 	return c, nil
 }
 
+// DerivedContract is the raw decoded shape of a single derived contract
+// resource, used by DiscoverMeterID to find the sensor domains it exposes.
+type DerivedContract struct {
+	SensorDomains []string `json:"sensorDomains"`
+}
+
 // DiscoverMeterID finds the first contract with sensor domains and sets the client's meter ID.
 // This is automatically called by NewClient if no meter ID is provided.
 // Returns an error if no contract with sensor domains is found.
@@ -114,6 +143,21 @@ func (c *Client) DiscoverMeterID() error {
 	return fmt.Errorf("no contract with sensor domains found")
 }
 
+// MeterReading is the raw decoded shape of the gateway's extended metering
+// response, an array of OBIS-coded values in the same logical-name/scaler/unit
+// shape the history archive uses (see historySampleDTO).
+type MeterReading struct {
+	Values []MeterValue `json:"values"`
+}
+
+// MeterValue is a single raw OBIS-coded value within a MeterReading.
+type MeterValue struct {
+	LogicalName string `json:"logicalName"`
+	Value       string `json:"value"`
+	Scaler      int    `json:"scaler"`
+	Unit        int    `json:"unit"`
+}
+
 // GetMeterValues fetches and parses current meter readings from the gateway.
 //
 // Returns a map of OBIS codes to float64 values. OBIS codes use the format C.D.E
@@ -124,6 +168,9 @@ func (c *Client) DiscoverMeterID() error {
 //   - 31.7.0, 51.7.0, 71.7.0: Phase currents (A)
 //   - 32.7.0, 52.7.0, 72.7.0: Phase voltages (V)
 //
+// See GetMeterReading for a strongly-typed alternative that names each
+// field instead of requiring callers to know the OBIS table.
+//
 // Returns an error if the gateway request fails or no valid values are found.
 func (c *Client) GetMeterValues() (map[string]float64, error) {
 	if c.meterID == "" {
@@ -150,20 +197,12 @@ func (c *Client) GetMeterValues() (map[string]float64, error) {
 			continue
 		}
 
-		val := raw * math.Pow(10, float64(item.Scaler))
-
-		switch item.Unit {
-		case 27: // W (Watt)
-			values[obis] = val
-		case 30: // Wh (Watthour) → kWh
-			values[obis] = val / 1000
-		case 33: // A (Ampere)
-			values[obis] = val
-		case 35: // V (Volt)
-			values[obis] = val
-		case 44: // Hz (Hertz)
-			values[obis] = val
+		val, ok := applyUnit(raw*math.Pow(10, float64(item.Scaler)), item.Unit)
+		if !ok {
+			continue
 		}
+
+		values[obis] = val
 	}
 
 	if len(values) == 0 {
@@ -173,6 +212,56 @@ func (c *Client) GetMeterValues() (map[string]float64, error) {
 	return values, nil
 }
 
+// GetMeterReading fetches current meter readings like GetMeterValues, but
+// returns them as a strongly-typed Reading instead of a bare OBIS-keyed
+// map. Use this when you want named fields (ActivePowerTotal, VoltageL1,
+// ...); use GetMeterValues when you want to iterate over everything the
+// gateway reports.
+//
+// Returns an error if the gateway request fails or no valid values are found.
+func (c *Client) GetMeterReading() (*Reading, error) {
+	if c.meterID == "" {
+		return nil, fmt.Errorf("meter ID not set")
+	}
+
+	var mr MeterReading
+	uri := fmt.Sprintf("%s/json/metering/origin/%s/extended", c.uri, c.meterID)
+
+	if err := c.getJSON(uri, &mr); err != nil {
+		return nil, fmt.Errorf("failed to get meter reading: %w", err)
+	}
+
+	result := &Reading{
+		Timestamp: time.Now(),
+		Raw:       make(map[OBIS]float64),
+	}
+
+	for _, item := range mr.Values {
+		code, err := convertToOBIS(item.LogicalName)
+		if err != nil {
+			continue
+		}
+
+		raw, err := strconv.ParseFloat(item.Value, 64)
+		if err != nil {
+			continue
+		}
+
+		val, ok := applyUnit(raw*math.Pow(10, float64(item.Scaler)), item.Unit)
+		if !ok {
+			continue
+		}
+
+		result.set(OBIS(code), val)
+	}
+
+	if len(result.Raw) == 0 {
+		return nil, fmt.Errorf("no valid meter values found")
+	}
+
+	return result, nil
+}
+
 // MeterID returns the currently configured meter ID.
 // This is set either explicitly during NewClient or discovered automatically.
 func (c *Client) MeterID() string {
@@ -203,6 +292,40 @@ func (c *Client) getJSON(uri string, result interface{}) error {
 	return nil
 }
 
+// applyUnit converts val according to its DLMS/COSEM unit code, scaling
+// energy-like units down to their commonly reported "per thousand" form
+// (Wh to kWh, VAh to kVAh, varh to kvarh). ok is false for units this
+// package doesn't know how to interpret, so callers can skip the value
+// instead of silently treating it as a different quantity.
+func applyUnit(val float64, unit int) (float64, bool) {
+	switch unit {
+	case 27: // W (Watt)
+		return val, true
+	case 28: // VA (Volt-Ampere)
+		return val, true
+	case 29: // var (Volt-Ampere reactive)
+		return val, true
+	case 30: // Wh (Watthour) → kWh
+		return val / 1000, true
+	case 31: // VAh (Volt-Ampere-hour) → kVAh
+		return val / 1000, true
+	case 32: // varh (Volt-Ampere-reactive-hour) → kvarh
+		return val / 1000, true
+	case 33: // A (Ampere)
+		return val, true
+	case 35: // V (Volt)
+		return val, true
+	case 44: // Hz (Hertz)
+		return val, true
+	case 23: // °C (degrees Celsius)
+		return val, true
+	case 61: // no unit (e.g. cos φ / power factor)
+		return val, true
+	default:
+		return 0, false
+	}
+}
+
 // convertToOBIS converts CASA logical name to OBIS C.D.E format
 func convertToOBIS(logicalName string) (string, error) {
 	hex := strings.SplitN(logicalName, ".", 2)[0]
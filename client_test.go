@@ -0,0 +1,103 @@
+package emhcasa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetMeterValuesAndGetMeterReading(t *testing.T) {
+	ln := logicalNameFor(16, 7, 0)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json/metering/origin/meter1/extended", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MeterReading{
+			Values: []MeterValue{
+				{LogicalName: ln, Value: "1500", Scaler: 0, Unit: 27},
+			},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "meter1", WithAuth(NewBasicAuth("user", "pass")))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	values, err := client.GetMeterValues()
+	if err != nil {
+		t.Fatalf("GetMeterValues: %v", err)
+	}
+	if values["16.7.0"] != 1500 {
+		t.Errorf("values[16.7.0] = %v, want 1500", values["16.7.0"])
+	}
+
+	reading, err := client.GetMeterReading()
+	if err != nil {
+		t.Fatalf("GetMeterReading: %v", err)
+	}
+	if reading.ActivePowerTotal != 1500 {
+		t.Errorf("ActivePowerTotal = %v, want 1500", reading.ActivePowerTotal)
+	}
+}
+
+func TestClient_DiscoverMeterID(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json/metering/derived", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]string{"contract1", "contract2"})
+	})
+	mux.HandleFunc("/json/metering/derived/contract1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DerivedContract{})
+	})
+	mux.HandleFunc("/json/metering/derived/contract2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DerivedContract{SensorDomains: []string{"meter42"}})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "", WithAuth(NewBasicAuth("user", "pass")))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if client.MeterID() != "meter42" {
+		t.Errorf("MeterID() = %q, want %q", client.MeterID(), "meter42")
+	}
+}
+
+func TestApplyUnit(t *testing.T) {
+	tests := []struct {
+		name   string
+		val    float64
+		unit   int
+		want   float64
+		wantOK bool
+	}{
+		{"watt", 100, 27, 100, true},
+		{"watthour to kWh", 1500, 30, 1.5, true},
+		{"varh to kvarh", 2000, 32, 2, true},
+		{"volt", 230, 35, 230, true},
+		{"hertz", 50, 44, 50, true},
+		{"unknown unit", 1, 255, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := applyUnit(tt.val, tt.unit)
+			if ok != tt.wantOK {
+				t.Fatalf("applyUnit(%v, %d) ok = %v, want %v", tt.val, tt.unit, ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("applyUnit(%v, %d) = %v, want %v", tt.val, tt.unit, got, tt.want)
+			}
+		})
+	}
+}
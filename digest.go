@@ -0,0 +1,356 @@
+package emhcasa
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// digestChallenge is the server's digest parameters for one host, cached
+// so we don't have to take a 401 round trip on every request.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	qop       string
+	algorithm string
+	nc        uint32
+}
+
+// digestTransport implements HTTP Digest authentication (RFC 7616) with
+// a cached nonce/realm/qop per host, a monotonically increasing nc, and
+// transparent re-authentication on a stale nonce. Small embedded
+// gateways tend to fall over if re-challenged on every request, so this
+// only takes the 401 round trip once per host.
+type digestTransport struct {
+	user, password string
+	base           http.RoundTripper
+
+	mu         sync.Mutex
+	challenges map[string]*digestChallenge
+
+	limiter *rateLimiter
+}
+
+// DigestOption configures a digestTransport created by NewDigestTransport.
+type DigestOption func(*digestTransport)
+
+// WithRateLimit caps the transport to rps requests per second, so
+// callers polling at high frequency don't overrun the gateway.
+func WithRateLimit(rps int) DigestOption {
+	return func(t *digestTransport) {
+		if rps > 0 {
+			t.limiter = newRateLimiter(rps)
+		}
+	}
+}
+
+// NewDigestTransport wraps base with HTTP Digest authentication.
+func NewDigestTransport(user, password string, base http.RoundTripper, opts ...DigestOption) http.RoundTripper {
+	t := &digestTransport{
+		user:       user,
+		password:   password,
+		base:       base,
+		challenges: make(map[string]*digestChallenge),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// RoundTrip sends req with a cached Authorization header when one is
+// available, transparently re-authenticates on a 401 (including a stale
+// nonce, which looks the same from here), and retries a 429/503 for as
+// long as fewer than three attempts have been made, first sleeping for
+// its Retry-After delay when the response carries one.
+func (t *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+
+	for attempt := 0; ; attempt++ {
+		if t.limiter != nil {
+			t.limiter.Wait()
+		}
+
+		var (
+			clone *http.Request
+			err   error
+		)
+		if attempt == 0 {
+			// First attempt: no need to rewind, req.Body is used at
+			// most once either way.
+			clone = req.Clone(req.Context())
+		} else {
+			clone, err = cloneRequestBody(req)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t.applyAuth(clone, host)
+
+		resp, err := t.base.RoundTrip(clone)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			challenge, err := parseDigestChallenge(resp.Header.Get("WWW-Authenticate"))
+			resp.Body.Close()
+			if err != nil {
+				return resp, nil
+			}
+			t.setChallenge(host, challenge)
+			continue
+
+		case (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) && attempt < 2:
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			if wait > 0 {
+				time.Sleep(wait)
+			}
+			continue
+
+		default:
+			return resp, nil
+		}
+	}
+}
+
+// applyAuth sets the Authorization header on req from the cached
+// challenge for host, incrementing nc. It is a no-op if no challenge has
+// been observed for host yet.
+func (t *digestTransport) applyAuth(req *http.Request, host string) {
+	t.mu.Lock()
+	challenge := t.challenges[host]
+	var nc uint32
+	if challenge != nil {
+		challenge.nc++
+		nc = challenge.nc
+	}
+	t.mu.Unlock()
+
+	if challenge == nil {
+		return
+	}
+
+	req.Header.Set("Authorization", t.digestHeader(req.Method, req.URL.RequestURI(), challenge, nc))
+}
+
+// setChallenge replaces the cached challenge for host, resetting nc.
+func (t *digestTransport) setChallenge(host string, challenge *digestChallenge) {
+	t.mu.Lock()
+	t.challenges[host] = challenge
+	t.mu.Unlock()
+}
+
+// digestHeader computes the Authorization header value for one request
+// against challenge, at counter nc.
+func (t *digestTransport) digestHeader(method, uri string, challenge *digestChallenge, nc uint32) string {
+	ha1 := md5Hex(fmt.Sprintf("%s:%s:%s", t.user, challenge.realm, t.password))
+	ha2 := md5Hex(fmt.Sprintf("%s:%s", method, uri))
+	ncStr := fmt.Sprintf("%08x", nc)
+	cnonce := generateCNonce()
+
+	if strings.EqualFold(challenge.algorithm, "MD5-sess") {
+		ha1 = md5Hex(strings.Join([]string{ha1, challenge.nonce, cnonce}, ":"))
+	}
+
+	var response string
+	if challenge.qop != "" {
+		response = md5Hex(strings.Join([]string{ha1, challenge.nonce, ncStr, cnonce, challenge.qop, ha2}, ":"))
+	} else {
+		response = md5Hex(strings.Join([]string{ha1, challenge.nonce, ha2}, ":"))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		t.user, challenge.realm, challenge.nonce, uri, response)
+
+	if challenge.opaque != "" {
+		fmt.Fprintf(&b, `, opaque="%s"`, challenge.opaque)
+	}
+	if challenge.qop != "" {
+		fmt.Fprintf(&b, `, qop=%s, nc=%s, cnonce="%s"`, challenge.qop, ncStr, cnonce)
+	}
+
+	return b.String()
+}
+
+// parseDigestChallenge parses a WWW-Authenticate: Digest header.
+func parseDigestChallenge(header string) (*digestChallenge, error) {
+	if !strings.HasPrefix(header, "Digest ") {
+		return nil, fmt.Errorf("unsupported auth challenge: %s", header)
+	}
+
+	params := parseAuthParams(strings.TrimPrefix(header, "Digest "))
+
+	challenge := &digestChallenge{
+		realm:     params["realm"],
+		nonce:     params["nonce"],
+		opaque:    params["opaque"],
+		qop:       firstQop(params["qop"]),
+		algorithm: params["algorithm"],
+	}
+
+	if challenge.nonce == "" {
+		return nil, fmt.Errorf("digest challenge missing nonce")
+	}
+
+	return challenge, nil
+}
+
+// firstQop picks "auth" out of a (possibly comma-separated) qop list,
+// the only quality of protection this transport implements; "auth-int"
+// requires hashing the request body into HA2, which digestHeader
+// doesn't do. Falls back to the first listed value if "auth" isn't
+// offered, which will produce a response the server rejects, but that's
+// no worse than not authenticating at all.
+func firstQop(qop string) string {
+	if qop == "" {
+		return ""
+	}
+
+	var first string
+	for i, v := range strings.Split(qop, ",") {
+		v = strings.TrimSpace(v)
+		if i == 0 {
+			first = v
+		}
+		if v == "auth" {
+			return v
+		}
+	}
+	return first
+}
+
+// parseAuthParams parses a comma-separated list of key=value or
+// key="value" pairs, as used by WWW-Authenticate and Authorization
+// headers.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+
+	for _, part := range splitAuthParams(s) {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		value := strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		params[key] = value
+	}
+
+	return params
+}
+
+// splitAuthParams splits s on commas that aren't inside a quoted value.
+func splitAuthParams(s string) []string {
+	var parts []string
+	var inQuotes bool
+	start := 0
+
+	for i, r := range s {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, s[start:])
+
+	return parts
+}
+
+// retryAfter parses a Retry-After header, which may be either a number
+// of seconds or an HTTP date. Returns 0 if header is empty or malformed.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
+// generateCNonce returns a random client nonce for one digest response.
+func generateCNonce() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// md5Hex returns the hex-encoded MD5 digest of s.
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// cloneRequestBody clones req, rewinding its body via GetBody if it has
+// one, so the same logical request can be retried after a challenge or
+// rate-limit response.
+func cloneRequestBody(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+
+	if req.Body == nil {
+		return clone, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("cannot retry request with a body that doesn't support rewinding (no GetBody)")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	clone.Body = body
+
+	return clone, nil
+}
+
+// rateLimiter is a simple leaky-bucket limiter enforcing a minimum
+// interval between requests.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(rps int) *rateLimiter {
+	return &rateLimiter{interval: time.Second / time.Duration(rps)}
+}
+
+// Wait blocks until the next request is allowed under the configured rate.
+func (l *rateLimiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,126 @@
+package emhcasa
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// extractAuthParam pulls a single key=value (or key="value") pair out of
+// an Authorization header value.
+func extractAuthParam(header, key string) string {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, key+"=") {
+			return strings.Trim(strings.TrimPrefix(part, key+"="), `"`)
+		}
+	}
+	return ""
+}
+
+func TestDigestTransport_SingleChallengePerSession(t *testing.T) {
+	var mu sync.Mutex
+	challengeCount := 0
+	var seenNC []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if auth == "" {
+			mu.Lock()
+			challengeCount++
+			mu.Unlock()
+
+			w.Header().Set("WWW-Authenticate", `Digest realm="casa", qop="auth", nonce="abc123"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		seenNC = append(seenNC, extractAuthParam(auth, "nc"))
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewDigestTransport("user", "pass", http.DefaultTransport)}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, resp.StatusCode)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if challengeCount != 1 {
+		t.Errorf("expected exactly one 401 challenge, got %d", challengeCount)
+	}
+
+	if len(seenNC) != 3 {
+		t.Fatalf("expected 3 authenticated requests, got %d", len(seenNC))
+	}
+
+	for i, nc := range seenNC {
+		want := fmt.Sprintf("%08x", i+1)
+		if nc != want {
+			t.Errorf("request %d: nc = %s, want %s", i, nc, want)
+		}
+	}
+}
+
+func TestDigestTransport_TransparentlyReauthenticatesOnStaleNonce(t *testing.T) {
+	var mu sync.Mutex
+	requestCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestCount++
+		n := requestCount
+		mu.Unlock()
+
+		auth := r.Header.Get("Authorization")
+		switch {
+		case auth == "":
+			w.Header().Set("WWW-Authenticate", `Digest realm="casa", qop="auth", nonce="nonce1", stale=false`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case n == 3: // the client's cached nonce1 has since expired server-side
+			w.Header().Set("WWW-Authenticate", `Digest realm="casa", qop="auth", nonce="nonce2", stale=true`)
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewDigestTransport("user", "pass", http.DefaultTransport)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("first request: expected 200, got %d", resp.StatusCode)
+	}
+
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("second request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("second request: expected transparent re-auth on stale nonce to yield 200, got %d", resp.StatusCode)
+	}
+}
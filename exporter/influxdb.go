@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// InfluxDBSink writes meter readings to an InfluxDB v2 bucket using the
+// line protocol, one point per push with a field per OBIS code.
+type InfluxDBSink struct {
+	url         string
+	org         string
+	bucket      string
+	token       string
+	measurement string
+	meterID     string
+
+	httpClient *http.Client
+}
+
+// NewInfluxDBSink creates a sink that writes to the given InfluxDB
+// server's /api/v2/write endpoint, tagging every point with meterID.
+func NewInfluxDBSink(url, org, bucket, token, meterID string) *InfluxDBSink {
+	return &InfluxDBSink{
+		url:         strings.TrimSuffix(url, "/"),
+		org:         org,
+		bucket:      bucket,
+		token:       token,
+		measurement: "emhcasa_reading",
+		meterID:     meterID,
+		httpClient:  http.DefaultClient,
+	}
+}
+
+// Push encodes values as a single line protocol point and writes it to
+// InfluxDB.
+func (s *InfluxDBSink) Push(values map[string]float64) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	line := s.encodeLine(values)
+
+	uri := fmt.Sprintf("%s/api/v2/write?org=%s&bucket=%s&precision=s", s.url, s.org, s.bucket)
+
+	req, err := http.NewRequest(http.MethodPost, uri, bytes.NewBufferString(line))
+	if err != nil {
+		return fmt.Errorf("failed to build influxdb request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+s.token)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write to influxdb: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("influxdb write failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// encodeLine renders values as a single InfluxDB line protocol point,
+// tagged with the meter ID and one field per OBIS code.
+func (s *InfluxDBSink) encodeLine(values map[string]float64) string {
+	var fields []string
+	for obis, value := range values {
+		key := "obis_" + strings.ReplaceAll(obis, ".", "_")
+		fields = append(fields, fmt.Sprintf("%s=%f", key, value))
+	}
+
+	return fmt.Sprintf("%s,meter_id=%s %s", s.measurement, s.meterID, strings.Join(fields, ","))
+}
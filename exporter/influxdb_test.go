@@ -0,0 +1,14 @@
+package exporter
+
+import "testing"
+
+func TestInfluxDBSink_EncodeLine(t *testing.T) {
+	sink := NewInfluxDBSink("http://influx.local", "org", "bucket", "token", "meter1")
+
+	line := sink.encodeLine(map[string]float64{"16.7.0": 1500})
+
+	want := "emhcasa_reading,meter_id=meter1 obis_16_7_0=1500.000000"
+	if line != want {
+		t.Errorf("encodeLine() = %q, want %q", line, want)
+	}
+}
@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MQTTSink publishes each OBIS reading to its own topic under a
+// configurable prefix. Total energy readings (OBIS group 1.8.x / 2.8.x)
+// are published as retained messages so subscribers joining late still
+// see the last known totals.
+type MQTTSink struct {
+	client mqtt.Client
+	prefix string
+	qos    byte
+}
+
+// NewMQTTSink creates a sink that publishes to an already-connected MQTT
+// client, using topicPrefix (e.g. "emhcasa/meter1") as the parent topic
+// for all OBIS readings.
+func NewMQTTSink(client mqtt.Client, topicPrefix string) *MQTTSink {
+	return &MQTTSink{
+		client: client,
+		prefix: strings.TrimSuffix(topicPrefix, "/"),
+		qos:    0,
+	}
+}
+
+// Push publishes each value under "<prefix>/<obis>", retaining totals.
+func (s *MQTTSink) Push(values map[string]float64) error {
+	for obis, value := range values {
+		topic := fmt.Sprintf("%s/%s", s.prefix, obis)
+		payload := strconv.FormatFloat(value, 'f', -1, 64)
+
+		token := s.client.Publish(topic, s.qos, isTotal(obis), payload)
+		if token.Wait() && token.Error() != nil {
+			return fmt.Errorf("failed to publish %s: %w", topic, token.Error())
+		}
+	}
+
+	return nil
+}
+
+// isTotal reports whether obis identifies a cumulative energy reading
+// (imported or exported total), which should be retained on the broker.
+func isTotal(obis string) bool {
+	return strings.HasPrefix(obis, "1.8.") || strings.HasPrefix(obis, "2.8.")
+}
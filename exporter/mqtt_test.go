@@ -0,0 +1,22 @@
+package exporter
+
+import "testing"
+
+func TestIsTotal(t *testing.T) {
+	tests := []struct {
+		obis string
+		want bool
+	}{
+		{"1.8.0", true},
+		{"2.8.0", true},
+		{"1.8.1", true},
+		{"16.7.0", false},
+		{"32.7.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := isTotal(tt.obis); got != tt.want {
+			t.Errorf("isTotal(%q) = %v, want %v", tt.obis, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package exporter turns an emhcasa.Client into a long-running collector
+// that repeatedly polls meter values and pushes them to one or more sinks.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/tobima/emh-casa-go"
+)
+
+// Sink receives meter readings produced by a Poller.
+//
+// Push is called once per poll cycle with the full set of OBIS-keyed
+// values returned by GetMeterValues. Implementations should treat the
+// map as read-only and return quickly; slow sinks delay the next poll.
+type Sink interface {
+	Push(values map[string]float64) error
+}
+
+// Poller repeatedly fetches meter values from a Client on a fixed
+// interval and forwards them to the configured sinks.
+type Poller struct {
+	client   *emhcasa.Client
+	interval time.Duration
+	jitter   time.Duration
+	onError  func(error)
+
+	mu    sync.Mutex
+	sinks []Sink
+}
+
+// PollerOption configures a Poller created by NewPoller.
+type PollerOption func(*Poller)
+
+// WithJitter adds a random duration in [0, jitter) to each poll interval,
+// so that multiple pollers hitting the same gateway don't synchronize.
+func WithJitter(jitter time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.jitter = jitter
+	}
+}
+
+// WithSinks adds one or more sinks that readings are pushed to.
+func WithSinks(sinks ...Sink) PollerOption {
+	return func(p *Poller) {
+		p.sinks = append(p.sinks, sinks...)
+	}
+}
+
+// WithErrorHandler sets a callback invoked when a poll or sink push fails.
+// If unset, errors are silently swallowed so one bad cycle doesn't stop
+// the poller.
+func WithErrorHandler(fn func(error)) PollerOption {
+	return func(p *Poller) {
+		p.onError = fn
+	}
+}
+
+// NewPoller creates a Poller that queries client every interval.
+func NewPoller(client *emhcasa.Client, interval time.Duration, opts ...PollerOption) *Poller {
+	p := &Poller{
+		client:   client,
+		interval: interval,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// AddSink registers an additional sink to push readings to. Safe to
+// call while Run is in progress.
+func (p *Poller) AddSink(sink Sink) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sinks = append(p.sinks, sink)
+}
+
+// Run polls until ctx is cancelled, blocking the calling goroutine.
+// Each cycle fetches meter values and pushes them to every sink; a
+// failure in one sink does not prevent the others from receiving the
+// reading.
+func (p *Poller) Run(ctx context.Context) error {
+	timer := time.NewTimer(p.nextDelay())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			p.poll()
+			timer.Reset(p.nextDelay())
+		}
+	}
+}
+
+// poll fetches one set of meter values and fans it out to all sinks.
+func (p *Poller) poll() {
+	values, err := p.client.GetMeterValues()
+	if err != nil {
+		p.handleError(fmt.Errorf("poll failed: %w", err))
+		return
+	}
+
+	p.mu.Lock()
+	sinks := make([]Sink, len(p.sinks))
+	copy(sinks, p.sinks)
+	p.mu.Unlock()
+
+	pushToSinks(sinks, values, p.handleError)
+}
+
+// pushToSinks pushes values to every sink, reporting each failure to
+// onFail without letting it stop the remaining sinks.
+func pushToSinks(sinks []Sink, values map[string]float64, onFail func(error)) {
+	for _, sink := range sinks {
+		if err := sink.Push(values); err != nil {
+			onFail(fmt.Errorf("sink push failed: %w", err))
+		}
+	}
+}
+
+func (p *Poller) handleError(err error) {
+	if p.onError != nil {
+		p.onError(err)
+	}
+}
+
+func (p *Poller) nextDelay() time.Duration {
+	if p.jitter <= 0 {
+		return p.interval
+	}
+	return p.interval + time.Duration(rand.Int63n(int64(p.jitter)))
+}
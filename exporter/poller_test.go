@@ -0,0 +1,64 @@
+package exporter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeSink struct {
+	pushed []map[string]float64
+	err    error
+}
+
+func (s *fakeSink) Push(values map[string]float64) error {
+	s.pushed = append(s.pushed, values)
+	return s.err
+}
+
+func TestPushToSinks_FansOutAndReportsFailures(t *testing.T) {
+	ok := &fakeSink{}
+	failing := &fakeSink{err: errors.New("boom")}
+
+	var errs []error
+	pushToSinks([]Sink{ok, failing}, map[string]float64{"16.7.0": 100}, func(err error) {
+		errs = append(errs, err)
+	})
+
+	if len(ok.pushed) != 1 {
+		t.Errorf("ok sink received %d pushes, want 1", len(ok.pushed))
+	}
+	if len(failing.pushed) != 1 {
+		t.Errorf("failing sink received %d pushes, want 1", len(failing.pushed))
+	}
+	if len(errs) != 1 {
+		t.Errorf("onFail called %d times, want 1", len(errs))
+	}
+}
+
+func TestPoller_NextDelay(t *testing.T) {
+	p := &Poller{interval: 10 * time.Second}
+	if got := p.nextDelay(); got != 10*time.Second {
+		t.Errorf("nextDelay() with no jitter = %v, want %v", got, 10*time.Second)
+	}
+
+	p = &Poller{interval: 10 * time.Second, jitter: 5 * time.Second}
+	for i := 0; i < 50; i++ {
+		got := p.nextDelay()
+		if got < 10*time.Second || got >= 15*time.Second {
+			t.Fatalf("nextDelay() with jitter = %v, want in [10s, 15s)", got)
+		}
+	}
+}
+
+func TestPoller_AddSink(t *testing.T) {
+	p := NewPoller(nil, time.Second)
+
+	s1, s2 := &fakeSink{}, &fakeSink{}
+	p.AddSink(s1)
+	p.AddSink(s2)
+
+	if len(p.sinks) != 2 {
+		t.Fatalf("len(p.sinks) = %d, want 2", len(p.sinks))
+	}
+}
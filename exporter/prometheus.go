@@ -0,0 +1,85 @@
+package exporter
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusSink exposes the most recently pushed meter readings as
+// Prometheus gauges, one per OBIS code, labeled by meter ID and phase.
+//
+// Use Handler to mount the collector on an HTTP server, typically at
+// "/metrics".
+type PrometheusSink struct {
+	meterID  string
+	registry *prometheus.Registry
+	gauges   map[string]*prometheus.GaugeVec
+
+	mu sync.Mutex
+}
+
+// NewPrometheusSink creates a PrometheusSink that labels every series
+// with meterID.
+func NewPrometheusSink(meterID string) *PrometheusSink {
+	return &PrometheusSink{
+		meterID:  meterID,
+		registry: prometheus.NewRegistry(),
+		gauges:   make(map[string]*prometheus.GaugeVec),
+	}
+}
+
+// Push updates the gauge for each OBIS code in values, registering new
+// gauges on first sight.
+func (s *PrometheusSink) Push(values map[string]float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for obis, value := range values {
+		gauge, ok := s.gauges[obis]
+		if !ok {
+			gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: "emhcasa",
+				Name:      "obis_" + strings.ReplaceAll(obis, ".", "_"),
+				Help:      "EMH CASA meter reading for OBIS code " + obis,
+			}, []string{"meter_id", "phase"})
+
+			if err := s.registry.Register(gauge); err != nil {
+				return err
+			}
+
+			s.gauges[obis] = gauge
+		}
+
+		gauge.WithLabelValues(s.meterID, phaseLabel(obis)).Set(value)
+	}
+
+	return nil
+}
+
+// Handler returns an http.Handler serving the registered gauges in the
+// Prometheus text exposition format, suitable for mounting at "/metrics".
+func (s *PrometheusSink) Handler() http.Handler {
+	return promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{})
+}
+
+// phaseLabel derives a phase label ("l1", "l2", "l3", or "total") from
+// an OBIS code's C value, which conventionally encodes the phase for
+// per-phase power, current, and voltage readings.
+func phaseLabel(obis string) string {
+	c := strings.SplitN(obis, ".", 2)[0]
+
+	switch c {
+	case "21", "22", "23", "31", "32", "81":
+		return "l1"
+	case "41", "42", "43", "51", "52", "82":
+		return "l2"
+	case "61", "62", "63", "71", "72", "83":
+		return "l3"
+	default:
+		return "total"
+	}
+}
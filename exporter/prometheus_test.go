@@ -0,0 +1,43 @@
+package exporter
+
+import "testing"
+
+func TestPhaseLabel(t *testing.T) {
+	tests := []struct {
+		obis string
+		want string
+	}{
+		{"21.7.0", "l1"},
+		{"32.7.0", "l1"},
+		{"41.7.0", "l2"},
+		{"52.7.0", "l2"},
+		{"61.7.0", "l3"},
+		{"72.7.0", "l3"},
+		{"16.7.0", "total"},
+		{"1.8.0", "total"},
+	}
+
+	for _, tt := range tests {
+		if got := phaseLabel(tt.obis); got != tt.want {
+			t.Errorf("phaseLabel(%q) = %q, want %q", tt.obis, got, tt.want)
+		}
+	}
+}
+
+func TestPrometheusSink_Push(t *testing.T) {
+	sink := NewPrometheusSink("meter1")
+
+	if err := sink.Push(map[string]float64{"16.7.0": 1500}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	// Pushing the same OBIS code again must update the existing gauge
+	// rather than trying (and failing) to register a duplicate.
+	if err := sink.Push(map[string]float64{"16.7.0": 1600}); err != nil {
+		t.Fatalf("second Push: %v", err)
+	}
+
+	if sink.Handler() == nil {
+		t.Error("Handler() returned nil")
+	}
+}
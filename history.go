@@ -0,0 +1,302 @@
+package emhcasa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistorySample is a single timestamped archive value for one OBIS code.
+type HistorySample struct {
+	OBIS      OBIS
+	Timestamp time.Time
+	Value     float64
+}
+
+// HistoryGap marks a period within a requested range where the archive
+// has no samples for an OBIS code, spanning more than one and a half
+// resolution steps.
+type HistoryGap struct {
+	OBIS  OBIS
+	Start time.Time
+	End   time.Time
+}
+
+// HistoryGapError is returned alongside the samples GetHistory did find
+// when the archive has gaps. The returned samples are still valid;
+// callers that don't care about gaps can ignore the error with
+// errors.As.
+type HistoryGapError struct {
+	Gaps []HistoryGap
+}
+
+func (e *HistoryGapError) Error() string {
+	return fmt.Sprintf("history archive has %d gap(s)", len(e.Gaps))
+}
+
+// historyPage is one page of the gateway's archive response.
+type historyPage struct {
+	Samples []historySampleDTO `json:"samples"`
+	Cursor  string             `json:"cursor"`
+}
+
+// historySampleDTO is a single raw archive entry, in the same
+// logical-name/scaler/unit shape as MeterReading's Values.
+type historySampleDTO struct {
+	LogicalName string `json:"logicalName"`
+	Timestamp   int64  `json:"timestamp"`
+	Value       string `json:"value"`
+	Scaler      int    `json:"scaler"`
+	Unit        int    `json:"unit"`
+}
+
+// toSample decodes dto into a HistorySample, applying the same
+// scaler/unit conversion as GetMeterValues.
+func (dto historySampleDTO) toSample() (HistorySample, error) {
+	code, err := convertToOBIS(dto.LogicalName)
+	if err != nil {
+		return HistorySample{}, err
+	}
+
+	raw, err := strconv.ParseFloat(dto.Value, 64)
+	if err != nil {
+		return HistorySample{}, err
+	}
+
+	val, ok := applyUnit(raw*math.Pow(10, float64(dto.Scaler)), dto.Unit)
+	if !ok {
+		return HistorySample{}, fmt.Errorf("unsupported unit %d", dto.Unit)
+	}
+
+	return HistorySample{
+		OBIS:      OBIS(code),
+		Timestamp: time.Unix(dto.Timestamp, 0),
+		Value:     val,
+	}, nil
+}
+
+// GetHistory queries the gateway's archive for obis between from and to,
+// at the given resolution, and returns the samples it found sorted by
+// timestamp. Large ranges are paginated internally.
+//
+// If the archive has gaps larger than 1.5x resolution, the samples
+// found are still returned, together with a *HistoryGapError describing
+// them; check for this with errors.As if your use case cares about gaps.
+func (c *Client) GetHistory(ctx context.Context, obis []string, from, to time.Time, resolution time.Duration) ([]HistorySample, error) {
+	if c.meterID == "" {
+		return nil, fmt.Errorf("meter ID not set")
+	}
+
+	var samples []HistorySample
+	cursor := ""
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := c.getHistoryPage(ctx, obis, from, to, resolution, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dto := range page.Samples {
+			sample, err := dto.toSample()
+			if err != nil {
+				continue
+			}
+			samples = append(samples, sample)
+		}
+
+		if page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+
+	if gaps := detectHistoryGaps(obis, from, to, resolution, samples); len(gaps) > 0 {
+		return samples, &HistoryGapError{Gaps: gaps}
+	}
+
+	return samples, nil
+}
+
+// StreamHistory is the streaming equivalent of GetHistory, for ranges
+// too large to hold in memory at once. Samples are delivered as each
+// page is fetched; both channels are closed when the range is exhausted
+// or ctx is cancelled. At most one error is ever sent.
+func (c *Client) StreamHistory(ctx context.Context, obis []string, from, to time.Time, resolution time.Duration) (<-chan HistorySample, <-chan error) {
+	samples := make(chan HistorySample)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(samples)
+		defer close(errs)
+
+		if c.meterID == "" {
+			errs <- fmt.Errorf("meter ID not set")
+			return
+		}
+
+		cursor := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+
+			page, err := c.getHistoryPage(ctx, obis, from, to, resolution, cursor)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			for _, dto := range page.Samples {
+				sample, err := dto.toSample()
+				if err != nil {
+					continue
+				}
+
+				select {
+				case samples <- sample:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+
+			if page.Cursor == "" {
+				return
+			}
+			cursor = page.Cursor
+		}
+	}()
+
+	return samples, errs
+}
+
+// Subscribe polls GetMeterReading every interval and delivers each
+// successful reading on the returned channel. The channel is closed
+// when ctx is cancelled. Poll errors are skipped rather than sent,
+// since there's no separate error channel to put them on; callers that
+// need to observe failures should build on GetMeterReading directly.
+func (c *Client) Subscribe(ctx context.Context, interval time.Duration) <-chan Reading {
+	ch := make(chan Reading)
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reading, err := c.GetMeterReading()
+				if err != nil {
+					continue
+				}
+
+				select {
+				case ch <- *reading:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// getHistoryPage fetches one page of the archive starting at cursor
+// ("" for the first page).
+func (c *Client) getHistoryPage(ctx context.Context, obis []string, from, to time.Time, resolution time.Duration, cursor string) (*historyPage, error) {
+	uri := fmt.Sprintf("%s/json/metering/origin/%s/history?obis=%s&from=%d&to=%d&resolution=%d",
+		c.uri, c.meterID, strings.Join(obis, ","), from.Unix(), to.Unix(), int64(resolution.Seconds()))
+	if cursor != "" {
+		uri += "&cursor=" + cursor
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build history request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var page historyPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode history page: %w", err)
+	}
+
+	return &page, nil
+}
+
+// detectHistoryGaps finds, per requested OBIS code, any stretch of the
+// [from, to] range spaced more than 1.5x resolution from its neighbors:
+// between from and the first sample, between consecutive samples, and
+// between the last sample and to. An OBIS code with no samples at all
+// is reported as a single gap spanning the whole range.
+func detectHistoryGaps(requested []string, from, to time.Time, resolution time.Duration, samples []HistorySample) []HistoryGap {
+	if resolution <= 0 {
+		return nil
+	}
+
+	byOBIS := make(map[OBIS][]time.Time)
+	for _, s := range samples {
+		byOBIS[s.OBIS] = append(byOBIS[s.OBIS], s.Timestamp)
+	}
+
+	threshold := resolution + resolution/2
+
+	var gaps []HistoryGap
+	for _, code := range requested {
+		obis, err := ParseOBIS(code)
+		if err != nil {
+			continue
+		}
+
+		times := byOBIS[obis]
+		if len(times) == 0 {
+			gaps = append(gaps, HistoryGap{OBIS: obis, Start: from, End: to})
+			continue
+		}
+
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+		if times[0].Sub(from) > threshold {
+			gaps = append(gaps, HistoryGap{OBIS: obis, Start: from, End: times[0]})
+		}
+
+		for i := 1; i < len(times); i++ {
+			if times[i].Sub(times[i-1]) > threshold {
+				gaps = append(gaps, HistoryGap{OBIS: obis, Start: times[i-1], End: times[i]})
+			}
+		}
+
+		if to.Sub(times[len(times)-1]) > threshold {
+			gaps = append(gaps, HistoryGap{OBIS: obis, Start: times[len(times)-1], End: to})
+		}
+	}
+
+	return gaps
+}
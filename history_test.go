@@ -0,0 +1,180 @@
+package emhcasa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// logicalNameFor builds a 12-hex-digit CASA logical name that
+// convertToOBIS decodes to the OBIS code C.D.E.
+func logicalNameFor(c, d, e int) string {
+	return fmt.Sprintf("0000%02x%02x%02x00", c, d, e)
+}
+
+func TestClient_GetHistory_PaginatesAndSortsSamples(t *testing.T) {
+	ln := logicalNameFor(16, 7, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			json.NewEncoder(w).Encode(historyPage{
+				Samples: []historySampleDTO{
+					{LogicalName: ln, Timestamp: 300, Value: "3000", Scaler: -1, Unit: 27},
+					{LogicalName: ln, Timestamp: 100, Value: "1000", Scaler: -1, Unit: 27},
+				},
+				Cursor: "page2",
+			})
+		case "page2":
+			json.NewEncoder(w).Encode(historyPage{
+				Samples: []historySampleDTO{
+					{LogicalName: ln, Timestamp: 200, Value: "2000", Scaler: -1, Unit: 27},
+				},
+			})
+		default:
+			t.Fatalf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "meter1", WithAuth(NewBasicAuth("user", "pass")))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	samples, err := client.GetHistory(context.Background(), []string{"16.7.0"},
+		time.Unix(0, 0), time.Unix(400, 0), 100*time.Second)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+
+	if len(samples) != 3 {
+		t.Fatalf("len(samples) = %d, want 3", len(samples))
+	}
+
+	for i, wantUnix := range []int64{100, 200, 300} {
+		if samples[i].Timestamp.Unix() != wantUnix {
+			t.Errorf("samples[%d].Timestamp = %v, want unix %d", i, samples[i].Timestamp, wantUnix)
+		}
+	}
+
+	if samples[0].Value != 100 {
+		t.Errorf("samples[0].Value = %v, want 100", samples[0].Value)
+	}
+}
+
+func TestClient_GetHistory_ReturnsGapErrorOnSparseArchive(t *testing.T) {
+	ln := logicalNameFor(16, 7, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(historyPage{
+			Samples: []historySampleDTO{
+				{LogicalName: ln, Timestamp: 100, Value: "1000", Scaler: 0, Unit: 27},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "meter1", WithAuth(NewBasicAuth("user", "pass")))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	samples, err := client.GetHistory(context.Background(), []string{"16.7.0"},
+		time.Unix(0, 0), time.Unix(1000, 0), 10*time.Second)
+
+	if len(samples) != 1 {
+		t.Fatalf("len(samples) = %d, want 1", len(samples))
+	}
+
+	var gapErr *HistoryGapError
+	if !errors.As(err, &gapErr) {
+		t.Fatalf("expected *HistoryGapError, got %v", err)
+	}
+	if len(gapErr.Gaps) == 0 {
+		t.Error("expected at least one gap to be reported")
+	}
+}
+
+func TestDetectHistoryGaps_MissingOBISReportsFullRangeGap(t *testing.T) {
+	from := time.Unix(0, 0)
+	to := time.Unix(1000, 0)
+
+	gaps := detectHistoryGaps([]string{"16.7.0"}, from, to, 10*time.Second, nil)
+
+	if len(gaps) != 1 {
+		t.Fatalf("len(gaps) = %d, want 1", len(gaps))
+	}
+	if !gaps[0].Start.Equal(from) || !gaps[0].End.Equal(to) {
+		t.Errorf("gap = %+v, want Start=%v End=%v", gaps[0], from, to)
+	}
+}
+
+func TestClient_StreamHistory_DeliversAllPages(t *testing.T) {
+	ln := logicalNameFor(16, 7, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("cursor") == "" {
+			json.NewEncoder(w).Encode(historyPage{
+				Samples: []historySampleDTO{{LogicalName: ln, Timestamp: 100, Value: "1", Scaler: 0, Unit: 27}},
+				Cursor:  "next",
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(historyPage{
+			Samples: []historySampleDTO{{LogicalName: ln, Timestamp: 200, Value: "2", Scaler: 0, Unit: 27}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "meter1", WithAuth(NewBasicAuth("user", "pass")))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	samplesCh, errCh := client.StreamHistory(ctx, []string{"16.7.0"}, time.Unix(0, 0), time.Unix(300, 0), 10*time.Second)
+
+	var got []HistorySample
+	for s := range samplesCh {
+		got = append(got, s)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("StreamHistory: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+}
+
+func TestClient_Subscribe_ClosesChannelOnContextCancel(t *testing.T) {
+	client := &Client{} // meterID unset: every poll fails fast, nothing is ever sent
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	ch := client.Subscribe(ctx, 20*time.Millisecond)
+
+	count := 0
+	for range ch {
+		count++
+	}
+
+	if count != 0 {
+		t.Errorf("expected no readings from a client with no meter ID, got %d", count)
+	}
+}
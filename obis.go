@@ -0,0 +1,78 @@
+package emhcasa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OBIS identifies a meter quantity by its C.D.E code, the subset of the
+// IEC 62056 OBIS numbering scheme that CASA gateways expose (the A and B
+// groups are fixed for electricity and are dropped by convertToOBIS).
+type OBIS string
+
+// obisInfo describes the semantic name and unit of an OBIS code, so
+// callers don't have to remember that "16.7.0" means total active power.
+type obisInfo struct {
+	name string
+	unit string
+}
+
+// obisRegistry maps known OBIS codes to their semantic name and unit.
+// It backs both Name/Unit lookups and the field assignment in
+// Client.GetMeterReading.
+var obisRegistry = map[OBIS]obisInfo{
+	"16.7.0": {"ActivePowerTotal", "W"},
+	"21.7.0": {"ActivePowerL1", "W"},
+	"41.7.0": {"ActivePowerL2", "W"},
+	"61.7.0": {"ActivePowerL3", "W"},
+	"1.8.0":  {"EnergyImportTotal", "kWh"},
+	"2.8.0":  {"EnergyExportTotal", "kWh"},
+	"32.7.0": {"VoltageL1", "V"},
+	"52.7.0": {"VoltageL2", "V"},
+	"72.7.0": {"VoltageL3", "V"},
+	"31.7.0": {"CurrentL1", "A"},
+	"51.7.0": {"CurrentL2", "A"},
+	"71.7.0": {"CurrentL3", "A"},
+	"14.7.0": {"Frequency", "Hz"},
+	"13.7.0": {"PowerFactor", ""},
+	"3.7.0":  {"ReactivePower", "var"},
+	"9.7.0":  {"ApparentPower", "VA"},
+	"3.8.0":  {"ReactiveEnergyImportTotal", "kvarh"},
+	"4.8.0":  {"ReactiveEnergyExportTotal", "kvarh"},
+}
+
+// ParseOBIS validates that s looks like a C.D.E OBIS code and returns it
+// as an OBIS. It does not require the code to be present in the
+// registry, since gateways may expose codes this package doesn't name.
+func ParseOBIS(s string) (OBIS, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid OBIS code %q", s)
+	}
+
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", fmt.Errorf("invalid OBIS code %q: %w", s, err)
+		}
+	}
+
+	return OBIS(s), nil
+}
+
+// String returns the OBIS code in C.D.E form.
+func (o OBIS) String() string {
+	return string(o)
+}
+
+// Name returns the semantic name of the quantity identified by o, e.g.
+// "ActivePowerTotal" for "16.7.0", or "" if o is not in the registry.
+func (o OBIS) Name() string {
+	return obisRegistry[o].name
+}
+
+// Unit returns the physical unit of the quantity identified by o, e.g.
+// "W" for "16.7.0", or "" if o is unitless or not in the registry.
+func (o OBIS) Unit() string {
+	return obisRegistry[o].unit
+}
@@ -0,0 +1,52 @@
+package emhcasa
+
+import "testing"
+
+func TestParseOBIS(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid", "16.7.0", false},
+		{"valid multi-digit", "123.45.6", false},
+		{"missing component", "16.7", true},
+		{"non-numeric", "a.b.c", true},
+		{"trailing garbage", "16.7.0garbage", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obis, err := ParseOBIS(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOBIS(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && obis.String() != tt.input {
+				t.Errorf("ParseOBIS(%q).String() = %q, want %q", tt.input, obis.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestOBIS_NameAndUnit(t *testing.T) {
+	tests := []struct {
+		obis     OBIS
+		wantName string
+		wantUnit string
+	}{
+		{"16.7.0", "ActivePowerTotal", "W"},
+		{"1.8.0", "EnergyImportTotal", "kWh"},
+		{"32.7.0", "VoltageL1", "V"},
+		{"99.9.9", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.obis.Name(); got != tt.wantName {
+			t.Errorf("%s.Name() = %q, want %q", tt.obis, got, tt.wantName)
+		}
+		if got := tt.obis.Unit(); got != tt.wantUnit {
+			t.Errorf("%s.Unit() = %q, want %q", tt.obis, got, tt.wantUnit)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package emhcasa
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// clientOptions collects the configuration assembled by functional
+// options passed to NewClient.
+type clientOptions struct {
+	auth       AuthMethod
+	tlsConfig  *tls.Config
+	httpClient *http.Client
+	hostHeader string
+	timeout    time.Duration
+}
+
+// Option configures a Client created by NewClient.
+type Option func(*clientOptions)
+
+// WithAuth sets the authentication method used to reach the gateway,
+// one of NewDigestAuth, NewBasicAuth, NewBearerAuth, or NewMTLSAuth.
+// Required unless WithHTTPClient supplies an already-authenticated
+// client.
+func WithAuth(auth AuthMethod) Option {
+	return func(o *clientOptions) {
+		o.auth = auth
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to reach the gateway,
+// e.g. to trust a self-signed certificate or pin a specific CA. If
+// unset, Digest/Basic/Bearer auth methods keep the historical default
+// of skipping certificate verification, since CASA gateways serve
+// self-signed certificates out of the box; mTLS verifies the server
+// normally using the CA pool passed to NewMTLSAuth. Pass this option to
+// opt into full verification for Digest/Basic/Bearer.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithHTTPClient overrides the underlying *http.Client entirely,
+// bypassing WithAuth, WithTLSConfig, and WithTimeout. Use this when you
+// need full control over the transport, e.g. for testing.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = hc
+	}
+}
+
+// WithHostHeader sets a custom Host header, for gateways reached through
+// a reverse proxy or load balancer where the request URI's host doesn't
+// match the certificate or routing rules. If unset, it's derived from
+// the client's uri.
+func WithHostHeader(host string) Option {
+	return func(o *clientOptions) {
+		o.hostHeader = host
+	}
+}
+
+// WithTimeout sets the HTTP client's request timeout. If unset, the
+// standard library default (no timeout) is used.
+func WithTimeout(d time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
@@ -0,0 +1,83 @@
+package emhcasa
+
+import "time"
+
+// Reading is a strongly-typed snapshot of the quantities this package
+// knows how to name, as an alternative to the bare OBIS-keyed map
+// returned by GetMeterValues. Fields are zero when the gateway didn't
+// report the corresponding OBIS code. Raw holds every decoded value,
+// including OBIS codes with no named field above.
+type Reading struct {
+	Timestamp time.Time
+
+	ActivePowerTotal float64
+	ActivePowerL1    float64
+	ActivePowerL2    float64
+	ActivePowerL3    float64
+
+	EnergyImportTotal float64
+	EnergyExportTotal float64
+
+	VoltageL1 float64
+	VoltageL2 float64
+	VoltageL3 float64
+
+	CurrentL1 float64
+	CurrentL2 float64
+	CurrentL3 float64
+
+	Frequency   float64
+	PowerFactor float64
+
+	ReactivePower             float64
+	ApparentPower             float64
+	ReactiveEnergyImportTotal float64
+	ReactiveEnergyExportTotal float64
+
+	Raw map[OBIS]float64
+}
+
+// set assigns val to the named field for obis, if one exists, and
+// always records it in Raw.
+func (r *Reading) set(obis OBIS, val float64) {
+	r.Raw[obis] = val
+
+	switch obis.Name() {
+	case "ActivePowerTotal":
+		r.ActivePowerTotal = val
+	case "ActivePowerL1":
+		r.ActivePowerL1 = val
+	case "ActivePowerL2":
+		r.ActivePowerL2 = val
+	case "ActivePowerL3":
+		r.ActivePowerL3 = val
+	case "EnergyImportTotal":
+		r.EnergyImportTotal = val
+	case "EnergyExportTotal":
+		r.EnergyExportTotal = val
+	case "VoltageL1":
+		r.VoltageL1 = val
+	case "VoltageL2":
+		r.VoltageL2 = val
+	case "VoltageL3":
+		r.VoltageL3 = val
+	case "CurrentL1":
+		r.CurrentL1 = val
+	case "CurrentL2":
+		r.CurrentL2 = val
+	case "CurrentL3":
+		r.CurrentL3 = val
+	case "Frequency":
+		r.Frequency = val
+	case "PowerFactor":
+		r.PowerFactor = val
+	case "ReactivePower":
+		r.ReactivePower = val
+	case "ApparentPower":
+		r.ApparentPower = val
+	case "ReactiveEnergyImportTotal":
+		r.ReactiveEnergyImportTotal = val
+	case "ReactiveEnergyExportTotal":
+		r.ReactiveEnergyExportTotal = val
+	}
+}
@@ -0,0 +1,24 @@
+package emhcasa
+
+import "testing"
+
+func TestReading_Set(t *testing.T) {
+	r := &Reading{Raw: make(map[OBIS]float64)}
+
+	r.set("16.7.0", 1500)
+	r.set("32.7.0", 230.5)
+	r.set("99.9.9", 42) // not in the registry
+
+	if r.ActivePowerTotal != 1500 {
+		t.Errorf("ActivePowerTotal = %v, want 1500", r.ActivePowerTotal)
+	}
+	if r.VoltageL1 != 230.5 {
+		t.Errorf("VoltageL1 = %v, want 230.5", r.VoltageL1)
+	}
+	if len(r.Raw) != 3 {
+		t.Errorf("len(Raw) = %d, want 3", len(r.Raw))
+	}
+	if r.Raw["99.9.9"] != 42 {
+		t.Errorf("Raw[99.9.9] = %v, want 42", r.Raw["99.9.9"])
+	}
+}
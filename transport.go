@@ -0,0 +1,17 @@
+package emhcasa
+
+import "net/http"
+
+// hostHeaderTransport overrides the Host header on every request, for
+// gateways reached by IP or through a reverse proxy where the request
+// URI's host doesn't match the certificate or routing rules.
+type hostHeaderTransport struct {
+	base http.RoundTripper
+	host string
+}
+
+func (t *hostHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Host = t.host
+	return t.base.RoundTrip(req)
+}